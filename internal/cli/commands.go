@@ -0,0 +1,27 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"io"
+
+	"github.com/mitchellh/cli"
+)
+
+// Commands returns the top-level command map for the nomad-pack CLI,
+// wired against stdout/stderr and a context cancelled on interrupt.
+func Commands(ctx context.Context, stdout, stderr io.Writer) map[string]cli.CommandFactory {
+	return map[string]cli.CommandFactory{
+		"run": func() (cli.Command, error) {
+			return &RunCommand{ctx: ctx, stdout: stdout, stderr: stderr}, nil
+		},
+		"plan": func() (cli.Command, error) {
+			return &PlanCommand{ctx: ctx, stdout: stdout, stderr: stderr}, nil
+		},
+		"shell": func() (cli.Command, error) {
+			return &ShellCommand{ctx: ctx, stdout: stdout, stderr: stderr}, nil
+		},
+	}
+}
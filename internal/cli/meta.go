@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/nomad-pack/internal/testui"
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+// outputMode selects which terminal.UI implementation a command uses.
+type outputMode string
+
+const (
+	outputHuman  outputMode = ""
+	outputJSON   outputMode = "json"
+	outputNDJSON outputMode = "ndjson"
+)
+
+// Meta holds the flags and UI selection shared by every pack command, so
+// RunCommand/PlanCommand/etc only need to add their own command-specific
+// flags on top of FlagSet.
+type Meta struct {
+	output   outputMode
+	debug    bool
+	progress bool
+}
+
+// FlagSet returns a flag.FlagSet pre-populated with the flags every pack
+// command accepts.
+func (m *Meta) FlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Var(&outputModeFlag{&m.output}, "output", `output format: "json" or "ndjson" (default: human-readable text)`)
+	fs.BoolVar(&m.debug, "debug", false, "print PackError stack traces on failure")
+	fs.BoolVar(&m.progress, "progress", false, "render live, in-place progress bars instead of scrolling status lines")
+	return fs
+}
+
+// UI constructs the terminal.UI a command should use for the --output and
+// --progress flags FlagSet parsed. --output wins over --progress: CI
+// consumers asking for json/ndjson get it even if --progress was also
+// (mistakenly) passed.
+func (m *Meta) UI(ctx context.Context, stdout, stderr io.Writer) terminal.UI {
+	var ui terminal.UI
+	switch {
+	case m.output == outputJSON || m.output == outputNDJSON:
+		ui = testui.StructuredJSONUI(ctx, stdout, stderr)
+	case m.progress:
+		ui = testui.LiveProgressTestUI(ctx, stdout, stderr)
+	default:
+		ui = testui.NonInteractiveTestUI(ctx, stdout, stderr)
+	}
+
+	if setter, ok := ui.(interface{ SetDebug(bool) }); ok {
+		setter.SetDebug(m.debug)
+	}
+	return ui
+}
+
+// outputModeFlag implements flag.Value so an unrecognized --output value
+// is rejected at parse time instead of silently falling back to human
+// output.
+type outputModeFlag struct {
+	dst *outputMode
+}
+
+func (f *outputModeFlag) String() string {
+	if f.dst == nil {
+		return ""
+	}
+	return string(*f.dst)
+}
+
+func (f *outputModeFlag) Set(value string) error {
+	switch outputMode(value) {
+	case outputJSON, outputNDJSON:
+		*f.dst = outputMode(value)
+		return nil
+	default:
+		return fmt.Errorf("invalid --output %q: must be %q or %q", value, outputJSON, outputNDJSON)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"io"
+
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+// PlanCommand implements `nomad-pack plan`, dry-running a pack's rendered
+// job specifications against the configured Nomad cluster without
+// submitting them.
+type PlanCommand struct {
+	Meta
+
+	ctx    context.Context
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (c *PlanCommand) Synopsis() string {
+	return "Dry-run a pack against a Nomad cluster"
+}
+
+func (c *PlanCommand) Help() string {
+	return `Usage: nomad-pack plan <pack>
+
+  Renders a pack's job specifications and submits them to Nomad's plan
+  endpoint, reporting the projected diff without deploying anything.
+
+General Options:
+
+  --output=<json|ndjson>  Emit machine-readable output instead of human text.
+  --progress              Render live, in-place progress bars per group.
+  --debug                 Print PackError stack traces on failure.
+`
+}
+
+func (c *PlanCommand) Run(args []string) int {
+	fs := c.FlagSet("plan")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	ui := c.UI(c.ctx, c.stdout, c.stderr)
+	defer closeUI(ui)
+
+	packs := fs.Args()
+	if len(packs) != 1 {
+		ui.Error("plan requires exactly one pack argument")
+		return 1
+	}
+
+	// See RunCommand.Run: stands in for the real render/plan loop until the
+	// pack loader and Nomad client exist in this tree.
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	step := sg.Add("planning %s", packs[0])
+	defer step.Done()
+
+	step.SubStep("rendering job specifications")
+	step.SubStep("submitting to Nomad plan endpoint")
+	step.Progress(1, 1, "groups planned")
+	step.Status(terminal.StatusOK)
+
+	return 0
+}
+
+// closeUI flushes a UI's masked writers if it implements Close, so the
+// command doesn't drop the last few bytes of output behind a registered
+// secret's tail buffer. See terminal.MaskingWriter.Close.
+func closeUI(ui terminal.UI) {
+	if closer, ok := ui.(interface{ Close() error }); ok {
+		closer.Close()
+	}
+}
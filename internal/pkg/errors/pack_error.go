@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"runtime"
+)
+
+// PackError is a structured error that carries its own details,
+// suggestions, and a captured stack trace, instead of requiring callers to
+// stuff that information into a flat []string context and recover it by
+// string-prefix matching. Wrap an error with Wrap or WrapWithContext at the
+// point it's first surfaced so the stack reflects where the problem
+// actually occurred.
+type PackError struct {
+	Cause       error
+	Details     []string
+	Suggestions []string
+	Fields      map[string]string
+	Stack       []uintptr
+}
+
+// Wrap creates a PackError around cause, capturing the current stack.
+func Wrap(cause error) *PackError {
+	return &PackError{
+		Cause: cause,
+		Stack: captureStack(),
+	}
+}
+
+// WrapWithContext creates a PackError around cause with the given details
+// and suggestions, capturing the current stack.
+func WrapWithContext(cause error, details, suggestions []string) *PackError {
+	return &PackError{
+		Cause:       cause,
+		Details:     details,
+		Suggestions: suggestions,
+		Stack:       captureStack(),
+	}
+}
+
+// captureStack captures the stack starting at the caller of whichever of
+// Wrap/WrapWithContext invoked it, so Frames()[0] is the real call site
+// regardless of which of the two public entry points was used.
+// Wrap used to forward to WrapWithContext, which meant Wrap's own frame
+// was skipped instead of captured; capturing independently in each
+// function avoids that footgun.
+func captureStack() []uintptr {
+	const depth = 32
+	pcs := make([]uintptr, depth)
+	// Skip runtime.Callers, captureStack, and the Wrap/WrapWithContext
+	// frame that called us.
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
+
+// WithField attaches a key/value pair to the error and returns it, so
+// construction can be chained: errors.Wrap(err).WithField("pack", name).
+func (e *PackError) WithField(key, value string) *PackError {
+	if e.Fields == nil {
+		e.Fields = make(map[string]string)
+	}
+	e.Fields[key] = value
+	return e
+}
+
+// Error implements error.
+func (e *PackError) Error() string {
+	if e.Cause == nil {
+		return "unknown error"
+	}
+	return e.Cause.Error()
+}
+
+// Unwrap allows errors.Is/errors.As and the standard unwrap loop to reach
+// the wrapped cause.
+func (e *PackError) Unwrap() error {
+	return e.Cause
+}
+
+// Frames resolves the captured program counters into runtime.Frames for
+// printing under --debug. Resolution is deferred to call time rather than
+// construction, per the runtime.Callers doc recommendation.
+func (e *PackError) Frames() []runtime.Frame {
+	frames := runtime.CallersFrames(e.Stack)
+	out := make([]runtime.Frame, 0, len(e.Stack))
+	for {
+		frame, more := frames.Next()
+		out = append(out, frame)
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+// FormatStack renders the captured stack as two lines per frame: the
+// function name, then its indented "file:line", suitable for printing under
+// a --debug flag.
+func (e *PackError) FormatStack() string {
+	var out string
+	for _, frame := range e.Frames() {
+		out += fmt.Sprintf("  %s\n    %s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+	return out
+}
+
+// Walk calls visit once for each *PackError found in err's cause chain,
+// outermost first. It advances past a match using that PackError's own
+// Unwrap() rather than re-running errors.As from the next Unwrap() of the
+// original error, so a single *PackError is never visited twice - which a
+// naive "for cause := err; cause != nil; cause = errors.Unwrap(cause)"
+// loop would do whenever a plain wrapper (fmt.Errorf("...: %w", packErr))
+// sits directly above it: errors.As would match that PackError while
+// cause is still the wrapper, and then match it again on the next
+// iteration once cause becomes the PackError itself.
+func Walk(err error, visit func(*PackError)) {
+	for err != nil {
+		var packErr *PackError
+		if !stderrors.As(err, &packErr) {
+			return
+		}
+		visit(packErr)
+		err = packErr.Unwrap()
+	}
+}
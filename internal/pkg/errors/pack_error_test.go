@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// callWrap exists solely so TestWrap_FramesTopIsCaller has a named frame to
+// assert against - it must be the top of the captured stack, not Wrap
+// itself.
+func callWrap(cause error) *PackError {
+	return Wrap(cause)
+}
+
+func TestWrap_FramesTopIsCaller(t *testing.T) {
+	packErr := callWrap(stderrors.New("boom"))
+
+	frames := packErr.Frames()
+	if len(frames) == 0 {
+		t.Fatal("expected at least one captured frame")
+	}
+	top := frames[0].Function
+	if !strings.HasSuffix(top, "callWrap") {
+		t.Fatalf("expected top frame to be the caller (callWrap), got %q", top)
+	}
+}
+
+func TestWalk_DoesNotVisitTwiceThroughPlainWrapper(t *testing.T) {
+	packErr := Wrap(stderrors.New("boom"))
+	wrapped := fmt.Errorf("while doing something: %w", packErr)
+
+	var visited int
+	Walk(wrapped, func(*PackError) {
+		visited++
+	})
+
+	if visited != 1 {
+		t.Fatalf("expected Walk to visit the PackError exactly once, visited %d times", visited)
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"io"
 	"regexp"
-	"slices"
 	"strings"
 	"sync"
 	"text/tabwriter"
@@ -25,16 +24,109 @@ type nonInteractiveTestUI struct {
 	mu        sync.Mutex
 	OutWriter io.Writer
 	ErrWriter io.Writer
+
+	// debug controls whether ErrorWithContext prints captured PackError
+	// stack traces. It's set from the CLI's --debug flag.
+	debug bool
+
+	// theme supplies the prefixes and colors for each style, looked up
+	// instead of hardcoding "\n» ", "! ", "warning: ", and the status
+	// glyphs. Falls back to terminal.DefaultTheme if nil.
+	theme *terminal.Theme
+
+	// outMask and errMask wrap OutWriter/ErrWriter so any value registered
+	// via RegisterSecret/RegisterSecretPattern is redacted before it
+	// reaches the terminal. Lazily installed by ensureMasks so a
+	// nonInteractiveTestUI built as a struct literal still gets masking
+	// once a secret is registered.
+	outMask *terminal.MaskingWriter
+	errMask *terminal.MaskingWriter
 }
 
 func NonInteractiveTestUI(ctx context.Context, stdout io.Writer, stderr io.Writer) terminal.UI {
+	theme, err := terminal.LoadTheme()
+	if err != nil {
+		theme = terminal.DefaultTheme
+	}
+
 	result := &nonInteractiveTestUI{
 		OutWriter: stdout,
 		ErrWriter: stderr,
+		theme:     &theme,
 	}
+	result.ensureMasks()
 	return result
 }
 
+// ensureMasks wraps OutWriter/ErrWriter in a MaskingWriter the first time
+// it's called, so RegisterSecret takes effect on every writer the UI
+// hands out, including ones already captured via OutputWriters().
+func (ui *nonInteractiveTestUI) ensureMasks() {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	if ui.outMask == nil {
+		ui.outMask = terminal.NewMaskingWriter(ui.OutWriter)
+		ui.OutWriter = ui.outMask
+	}
+	if ui.errMask == nil {
+		ui.errMask = terminal.NewMaskingWriter(ui.ErrWriter)
+		ui.ErrWriter = ui.errMask
+	}
+}
+
+// RegisterSecret implements UI, redacting value from every Output/Info/
+// Error/NamedValues/Table/Status message and every writer obtained via
+// OutputWriters() or a step's TermOutput().
+func (ui *nonInteractiveTestUI) RegisterSecret(value string) {
+	ui.ensureMasks()
+	ui.outMask.RegisterSecret(value)
+	ui.errMask.RegisterSecret(value)
+}
+
+// RegisterSecretPattern implements UI. See RegisterSecret.
+func (ui *nonInteractiveTestUI) RegisterSecretPattern(re *regexp.Regexp) {
+	ui.ensureMasks()
+	ui.outMask.RegisterSecretPattern(re)
+	ui.errMask.RegisterSecretPattern(re)
+}
+
+// SetDebug toggles whether ErrorWithContext prints PackError stack traces.
+func (ui *nonInteractiveTestUI) SetDebug(debug bool) {
+	ui.debug = debug
+}
+
+// Close flushes outMask/errMask so the last few bytes of output withheld
+// to catch a secret split across writes aren't silently dropped. Callers
+// that construct a UI for the lifetime of a single command should Close it
+// once they're done writing to it.
+func (ui *nonInteractiveTestUI) Close() error {
+	ui.mu.Lock()
+	outMask, errMask := ui.outMask, ui.errMask
+	ui.mu.Unlock()
+
+	var err error
+	if outMask != nil {
+		err = outMask.Close()
+	}
+	if errMask != nil {
+		if errMaskErr := errMask.Close(); err == nil {
+			err = errMaskErr
+		}
+	}
+	return err
+}
+
+// Theme returns the active theme, falling back to terminal.DefaultTheme
+// for a zero-value nonInteractiveTestUI (e.g. one embedded by another UI
+// that didn't go through NonInteractiveTestUI).
+func (ui *nonInteractiveTestUI) Theme() *terminal.Theme {
+	if ui.theme == nil {
+		return &terminal.DefaultTheme
+	}
+	return ui.theme
+}
+
 func (ui *nonInteractiveTestUI) Input(input *terminal.Input) (string, error) {
 	return "", terminal.ErrNonInteractive
 }
@@ -50,15 +142,16 @@ func (ui *nonInteractiveTestUI) Output(msg string, raw ...any) {
 	defer ui.mu.Unlock()
 	msg, style, _ := terminal.Interpret(msg, raw...)
 	w := ui.OutWriter
+	theme := ui.Theme()
 	switch style {
 	case terminal.DebugStyle:
-		msg = "debug: " + msg
+		msg = theme.Debug.Prefix + msg
 	case terminal.HeaderStyle:
-		msg = "\n» " + msg
+		msg = theme.Header.Color().Sprint(theme.Header.Prefix + msg)
 	case terminal.ErrorStyle, terminal.ErrorBoldStyle:
 		lines := strings.Split(msg, "\n")
 		if len(lines) > 0 {
-			fmt.Fprintln(w, "! "+lines[0])
+			fmt.Fprintln(w, theme.Error.Color().Sprint(theme.Error.Prefix+lines[0]))
 			for _, line := range lines[1:] {
 				fmt.Fprintln(w, "  "+line)
 			}
@@ -66,15 +159,16 @@ func (ui *nonInteractiveTestUI) Output(msg string, raw ...any) {
 
 		return
 	case terminal.WarningStyle, terminal.WarningBoldStyle:
-		msg = "warning: " + msg
+		msg = theme.Warning.Color().Sprint(theme.Warning.Prefix + msg)
 	case terminal.TraceStyle:
-		msg = "trace: " + msg
+		msg = theme.Trace.Prefix + msg
 	case terminal.SuccessStyle, terminal.SuccessBoldStyle:
+		msg = theme.Success.Color().Sprint(theme.Success.Prefix + msg)
 
 	case terminal.InfoStyle:
 		lines := strings.Split(msg, "\n")
 		for i, line := range lines {
-			lines[i] = colorInfo.Sprintf("  %s", line)
+			lines[i] = theme.Info.Color().Sprintf("%s%s", theme.Info.Prefix, line)
 		}
 
 		msg = strings.Join(lines, "\n")
@@ -89,13 +183,14 @@ func (ui *nonInteractiveTestUI) AppendToRow(msg string, raw ...any) {
 	defer ui.mu.Unlock()
 	msg, style, _ := terminal.Interpret(msg, raw...)
 	w := ui.OutWriter
+	theme := ui.Theme()
 	switch style {
 	case terminal.HeaderStyle:
-		msg = "\n» " + msg
+		msg = theme.Header.Color().Sprint(theme.Header.Prefix + msg)
 	case terminal.ErrorStyle, terminal.ErrorBoldStyle:
 		lines := strings.Split(msg, "\n")
 		if len(lines) > 0 {
-			fmt.Fprintln(w, "! "+lines[0])
+			fmt.Fprintln(w, theme.Error.Color().Sprint(theme.Error.Prefix+lines[0]))
 			for _, line := range lines[1:] {
 				fmt.Fprintln(w, "  "+line)
 			}
@@ -104,14 +199,15 @@ func (ui *nonInteractiveTestUI) AppendToRow(msg string, raw ...any) {
 		return
 
 	case terminal.WarningStyle, terminal.WarningBoldStyle:
-		msg = "warning: " + msg
+		msg = theme.Warning.Color().Sprint(theme.Warning.Prefix + msg)
 
 	case terminal.SuccessStyle, terminal.SuccessBoldStyle:
+		msg = theme.Success.Color().Sprint(theme.Success.Prefix + msg)
 
 	case terminal.InfoStyle:
 		lines := strings.Split(msg, "\n")
 		for i, line := range lines {
-			lines[i] = colorInfo.Sprintf("  %s", line)
+			lines[i] = theme.Info.Color().Sprintf("%s%s", theme.Info.Prefix, line)
 		}
 
 		msg = strings.Join(lines, "\n")
@@ -156,11 +252,13 @@ func (ui *nonInteractiveTestUI) OutputWriters() (io.Writer, io.Writer, error) {
 
 // Status implements UI
 func (ui *nonInteractiveTestUI) Status() terminal.Status {
-	return &nonInteractiveStatus{mu: &ui.mu}
+	ui.ensureMasks()
+	return &nonInteractiveStatus{mu: &ui.mu, theme: ui.Theme(), outMask: ui.outMask}
 }
 
 func (ui *nonInteractiveTestUI) StepGroup() terminal.StepGroup {
-	return &nonInteractiveStepGroup{mu: &ui.mu}
+	ui.ensureMasks()
+	return &nonInteractiveStepGroup{mu: &ui.mu, theme: ui.Theme(), outMask: ui.outMask}
 }
 
 // Table implements UI
@@ -189,32 +287,32 @@ func (ui *nonInteractiveTestUI) ErrorWithContext(err error, sub string, ctx ...s
 	ui.Error(helper.Title(sub))
 	ui.Error("  Error: " + err.Error())
 
-	// Selectively promote Details and Suggestion from the context.
-	var extractItem = func(ctx []string, key string) ([]string, string, bool) {
-		for i, v := range ctx {
-			if strings.HasPrefix(v, key) {
-				outStr := v
-				outCtx := slices.Delete(ctx, i, i+1)
-				return outCtx, outStr, true
-			}
+	// Walk the cause chain, printing each layer's details and suggestions.
+	// A plain error (not wrapped in a PackError anywhere in its chain)
+	// falls straight through to the legacy string-prefix parsing below.
+	errors.Walk(err, func(packErr *errors.PackError) {
+		for _, detail := range packErr.Details {
+			ui.Error("  Detail: " + detail)
 		}
-		return ctx, "", false
-	}
+		for _, suggestion := range packErr.Suggestions {
+			ui.Error("  Suggestion: " + suggestion)
+		}
+		if ui.debug && len(packErr.Stack) > 0 {
+			ui.Error("  Stack:")
+			ui.Error(packErr.FormatStack())
+		}
+	})
+
+	// Legacy path: selectively promote Details and Suggestion from the
+	// flat context slice for callers that haven't migrated to PackError.
 	var promote = func(key string) {
-		if oc, item, found := extractItem(ctx, key); found {
+		if oc, value, found := errors.ExtractContextItem(ctx, key); found {
 			ctx = oc
-			splits := strings.Split(item, ": ")
-
-			switch len(splits) {
-			case 0:
-				// no-op
-			case 1:
-				// There is something odd going on if we don't get a 2 split
-				// if we get 1, print the whole thing out.
-				ui.Error("  " + splits[0])
-			default:
-				ui.Error("  " + splits[0] + ": " + strings.Join(splits[1:], ": "))
+			if value == "" {
+				ui.Error("  " + key)
+				return
 			}
+			ui.Error("  " + key + ": " + value)
 		}
 	}
 
@@ -265,35 +363,52 @@ func (ui *nonInteractiveTestUI) WarningBold(msg string) {
 }
 
 type nonInteractiveStatus struct {
-	mu *sync.Mutex
+	mu      *sync.Mutex
+	theme   *terminal.Theme
+	outMask *terminal.MaskingWriter
+}
+
+func (s *nonInteractiveStatus) out() io.Writer {
+	if s.outMask != nil {
+		return s.outMask
+	}
+	return color.Output
 }
 
 func (s *nonInteractiveStatus) Update(msg string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Fprintln(color.Output, msg)
+	fmt.Fprintln(s.out(), msg)
 }
 
 func (s *nonInteractiveStatus) Step(status, msg string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Fprintf(color.Output, "%s: %s\n", textStatus[status], msg)
+	fmt.Fprintf(s.out(), "%s: %s\n", statusGlyph(s.theme, status), msg)
 }
 
+// Close flushes the status's outMask so any tail bytes withheld to catch a
+// secret split across writes aren't silently dropped once the status is
+// done being updated.
 func (s *nonInteractiveStatus) Close() error {
+	if s.outMask != nil {
+		return s.outMask.Close()
+	}
 	return nil
 }
 
 type nonInteractiveStepGroup struct {
-	mu     *sync.Mutex
-	wg     sync.WaitGroup
-	closed bool
+	mu      *sync.Mutex
+	theme   *terminal.Theme
+	outMask *terminal.MaskingWriter
+	wg      sync.WaitGroup
+	closed  bool
 }
 
 // Start a step in the output
 func (f *nonInteractiveStepGroup) Add(str string, args ...any) terminal.Step {
 	// Build our step
-	step := &nonInteractiveStep{mu: f.mu}
+	step := &nonInteractiveStep{mu: f.mu, theme: f.theme, outMask: f.outMask}
 
 	// Setup initial status
 	step.Update(str, args...)
@@ -321,26 +436,53 @@ func (f *nonInteractiveStepGroup) Wait() {
 	f.mu.Unlock()
 
 	wg.Wait()
+
+	// Flush any tail bytes outMask withheld to catch a secret split across
+	// writes - no further steps will be added to this group once Wait
+	// returns.
+	if f.outMask != nil {
+		f.outMask.Close()
+	}
 }
 
 type nonInteractiveStep struct {
-	mu   *sync.Mutex
-	wg   *sync.WaitGroup
-	done bool
+	mu      *sync.Mutex
+	theme   *terminal.Theme
+	outMask *terminal.MaskingWriter
+	wg      *sync.WaitGroup
+	done    bool
 }
 
 func (f *nonInteractiveStep) TermOutput() io.Writer {
+	if f.outMask != nil {
+		return &stripAnsiWriter{Next: f.outMask}
+	}
 	return &stripAnsiWriter{Next: color.Output}
 }
 
+func (f *nonInteractiveStep) out() io.Writer {
+	if f.outMask != nil {
+		return f.outMask
+	}
+	return color.Output
+}
+
 func (f *nonInteractiveStep) Update(str string, args ...any) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	fmt.Fprintln(color.Output, "-> "+fmt.Sprintf(str, args...))
+	fmt.Fprintln(f.out(), "-> "+fmt.Sprintf(str, args...))
 }
 
 func (f *nonInteractiveStep) Status(status string) {}
 
+// Progress implements terminal.Step. nonInteractiveStep has no concept of a
+// live-updating terminal, so progress ticks are dropped rather than
+// spamming a new line per call.
+func (f *nonInteractiveStep) Progress(current, total int64, unit string) {}
+
+// SubStep implements terminal.Step. See Progress.
+func (f *nonInteractiveStep) SubStep(name string) {}
+
 func (f *nonInteractiveStep) Done() {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -370,13 +512,22 @@ func (w *stripAnsiWriter) Write(p []byte) (n int, err error) {
 
 var reAnsi = regexp.MustCompile("[\u001B\u009B][[\\]()#;?]*(?:(?:(?:[a-zA-Z\\d]*(?:;[a-zA-Z\\d]*)*)?\u0007)|(?:(?:\\d{1,4}(?:;\\d{0,4})*)?[\\dA-PRZcf-ntqry=><~]))")
 
-var (
-	colorInfo = color.New()
-)
-
-var textStatus = map[string]string{
-	terminal.StatusOK:      " +",
-	terminal.StatusError:   " !",
-	terminal.StatusWarn:    " *",
-	terminal.StatusTimeout: "<>",
+// statusGlyph looks up the glyph for a step status from the active theme,
+// falling back to terminal.DefaultTheme if none was set.
+func statusGlyph(theme *terminal.Theme, status string) string {
+	if theme == nil {
+		theme = &terminal.DefaultTheme
+	}
+	switch status {
+	case terminal.StatusOK:
+		return theme.StatusOK
+	case terminal.StatusError:
+		return theme.StatusError
+	case terminal.StatusWarn:
+		return theme.StatusWarn
+	case terminal.StatusTimeout:
+		return theme.StatusTimeout
+	default:
+		return status
+	}
 }
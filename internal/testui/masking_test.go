@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testui
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+func TestNonInteractiveTestUI_RegisterSecretRedactsAppendToRow(t *testing.T) {
+	var out bytes.Buffer
+	ui := NonInteractiveTestUI(context.Background(), &out, &bytes.Buffer{})
+	ui.RegisterSecret("s3cr3t-token")
+
+	ui.AppendToRow("token is s3cr3t-token")
+	if got := out.String(); strings.Contains(got, "s3cr3t-token") {
+		t.Fatalf("secret leaked through AppendToRow: %q", got)
+	}
+}
+
+func TestNonInteractiveTestUI_RegisterSecretRedactsNamedValues(t *testing.T) {
+	var out bytes.Buffer
+	ui := NonInteractiveTestUI(context.Background(), &out, &bytes.Buffer{})
+	ui.RegisterSecret("s3cr3t-token")
+
+	ui.NamedValues([]terminal.NamedValue{{Name: "token", Value: "s3cr3t-token"}})
+	if got := out.String(); strings.Contains(got, "s3cr3t-token") {
+		t.Fatalf("secret leaked through tabwriter-formatted NamedValues: %q", got)
+	}
+}
+
+func TestNonInteractiveTestUI_RegisterSecretRedactsStepUpdate(t *testing.T) {
+	var out bytes.Buffer
+	ui := NonInteractiveTestUI(context.Background(), &out, &bytes.Buffer{})
+	ui.RegisterSecret("s3cr3t-token")
+
+	sg := ui.StepGroup()
+	step := sg.Add("fetching s3cr3t-token")
+	step.Done()
+	sg.Wait()
+
+	if got := out.String(); strings.Contains(got, "s3cr3t-token") {
+		t.Fatalf("secret leaked through step Update: %q", got)
+	}
+}
+
+// TestLiveProgressUI_RegisterSecretRedactsStepLabels exercises the
+// liveStepGroup/liveStep mask helpers directly rather than asserting on
+// uiprogress's rendered output, since uiprogress repaints on its own
+// ticker and Stop() gives no synchronous guarantee the final label was
+// ever flushed to the underlying writer.
+func TestLiveProgressUI_RegisterSecretRedactsStepLabels(t *testing.T) {
+	var out bytes.Buffer
+	ui := LiveProgressTestUI(context.Background(), &out, &bytes.Buffer{}).(*LiveProgressUI)
+	ui.RegisterSecret("s3cr3t-token")
+
+	sg := ui.StepGroup().(*liveStepGroup)
+	if got := sg.mask("fetching s3cr3t-token"); strings.Contains(got, "s3cr3t-token") {
+		t.Fatalf("secret leaked through liveStepGroup.mask: %q", got)
+	}
+
+	step := sg.Add("step").(*liveStep)
+	if got := step.mask("uploading s3cr3t-token"); strings.Contains(got, "s3cr3t-token") {
+		t.Fatalf("secret leaked through liveStep.mask: %q", got)
+	}
+	step.Done()
+	sg.Wait()
+}
+
+// TestLiveProgressUI_ProgressDrivesBarFill exercises liveStep.Progress's
+// effect on the underlying uiprogress.Bar directly - its Total/Current
+// fields, not uiprogress's asynchronously-repainted rendered output (see
+// the comment on TestLiveProgressUI_RegisterSecretRedactsStepLabels above
+// for why).
+func TestLiveProgressUI_ProgressDrivesBarFill(t *testing.T) {
+	var out bytes.Buffer
+	ui := LiveProgressTestUI(context.Background(), &out, &bytes.Buffer{}).(*LiveProgressUI)
+
+	sg := ui.StepGroup().(*liveStepGroup)
+	step := sg.Add("step").(*liveStep)
+
+	step.Progress(1, 4, "allocations")
+	if step.bar.Total != 4 {
+		t.Fatalf("expected bar.Total = 4, got %d", step.bar.Total)
+	}
+	if got := step.bar.Current(); got != 1 {
+		t.Fatalf("expected bar.Current() = 1, got %d", got)
+	}
+
+	step.Progress(3, 4, "allocations")
+	if got := step.bar.Current(); got != 3 {
+		t.Fatalf("expected bar.Current() = 3, got %d", got)
+	}
+
+	step.Done()
+	if got := step.bar.Current(); got != step.bar.Total {
+		t.Fatalf("expected Done to fill the bar to Total (%d), got %d", step.bar.Total, got)
+	}
+	sg.Wait()
+}
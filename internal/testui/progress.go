@@ -0,0 +1,190 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/gosuri/uilive"
+	"github.com/gosuri/uiprogress"
+
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+// LiveProgressUI is a terminal.UI backend that renders concurrent,
+// multi-line progress in place, instead of scrolling a wall of "-> msg"
+// lines the way nonInteractiveTestUI does. It's meant for watching a
+// multi-group job deployment: allocations placed/running/healthy, task
+// group counts, and registry download bytes all update in a live table.
+type LiveProgressUI struct {
+	nonInteractiveTestUI
+
+	live *uilive.Writer
+}
+
+// LiveProgressTestUI constructs a LiveProgressUI writing to stdout/stderr.
+// Like nonInteractiveTestUI, output is serialized behind a single mutex so
+// concurrent steps don't interleave writes.
+func LiveProgressTestUI(ctx context.Context, stdout io.Writer, stderr io.Writer) terminal.UI {
+	live := uilive.New()
+	live.Out = stdout
+
+	theme, err := terminal.LoadTheme()
+	if err != nil {
+		theme = terminal.DefaultTheme
+	}
+
+	ui := &LiveProgressUI{
+		nonInteractiveTestUI: nonInteractiveTestUI{
+			OutWriter: live,
+			ErrWriter: stderr,
+			theme:     &theme,
+		},
+		live: live,
+	}
+	ui.ensureMasks()
+	return ui
+}
+
+func (ui *LiveProgressUI) StepGroup() terminal.StepGroup {
+	ui.ensureMasks()
+
+	bars := uiprogress.New()
+	bars.SetOut(ui.live)
+	bars.Start()
+
+	return &liveStepGroup{mu: &ui.mu, theme: ui.Theme(), outMask: ui.outMask, bars: bars}
+}
+
+// liveStepGroup renders each step as a uiprogress.Bar so multiple
+// concurrently running steps repaint in place below one another, rather
+// than each printing a new line.
+type liveStepGroup struct {
+	mu      *sync.Mutex
+	theme   *terminal.Theme
+	outMask *terminal.MaskingWriter
+	wg      sync.WaitGroup
+	bars    *uiprogress.Progress
+	closed  bool
+}
+
+func (g *liveStepGroup) Add(str string, args ...any) terminal.Step {
+	bar := g.bars.AddBar(1)
+	label := g.mask(fmt.Sprintf(str, args...))
+	bar.PrependFunc(func(b *uiprogress.Bar) string {
+		return label
+	})
+
+	step := &liveStep{mu: g.mu, theme: g.theme, outMask: g.outMask, bar: bar}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.closed {
+		step.wg = &g.wg
+		g.wg.Add(1)
+	}
+	return step
+}
+
+func (g *liveStepGroup) mask(s string) string {
+	if g.outMask != nil {
+		return g.outMask.MaskString(s)
+	}
+	return s
+}
+
+func (g *liveStepGroup) Wait() {
+	g.mu.Lock()
+	g.closed = true
+	wg := &g.wg
+	g.mu.Unlock()
+
+	wg.Wait()
+	g.bars.Stop()
+
+	// Flush any tail bytes outMask withheld to catch a secret split across
+	// writes - no further steps will be added to this group once Wait
+	// returns.
+	if g.outMask != nil {
+		g.outMask.Close()
+	}
+}
+
+type liveStep struct {
+	mu      *sync.Mutex
+	theme   *terminal.Theme
+	outMask *terminal.MaskingWriter
+	wg      *sync.WaitGroup
+	bar     *uiprogress.Bar
+	done    bool
+}
+
+func (s *liveStep) mask(str string) string {
+	if s.outMask != nil {
+		return s.outMask.MaskString(str)
+	}
+	return str
+}
+
+func (s *liveStep) TermOutput() io.Writer {
+	return &stripAnsiWriter{Next: io.Discard}
+}
+
+func (s *liveStep) Update(str string, args ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg := s.mask(fmt.Sprintf(str, args...))
+	s.bar.PrependFunc(func(b *uiprogress.Bar) string { return msg })
+}
+
+func (s *liveStep) Status(status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	glyph := statusGlyph(s.theme, status)
+	s.bar.AppendFunc(func(b *uiprogress.Bar) string { return glyph })
+}
+
+// Progress implements terminal.Step, driving the bar's fill from the
+// reported current/total rather than a fixed increment per call.
+func (s *liveStep) Progress(current, total int64, unit string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if total > 0 {
+		s.bar.Total = int(total)
+	}
+	s.bar.Set(int(current))
+	label := s.mask(fmt.Sprintf("%d/%d %s", current, total, unit))
+	s.bar.AppendFunc(func(b *uiprogress.Bar) string {
+		return label
+	})
+}
+
+// SubStep implements terminal.Step by appending the sub-step name so it's
+// visible alongside the bar without allocating a new line.
+func (s *liveStep) SubStep(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	label := s.mask(name)
+	s.bar.AppendFunc(func(b *uiprogress.Bar) string { return label })
+}
+
+func (s *liveStep) Done() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return
+	}
+	s.done = true
+	s.bar.Set(s.bar.Total)
+	if s.wg != nil {
+		s.wg.Done()
+	}
+}
+
+func (s *liveStep) Abort() {
+	s.Done()
+}
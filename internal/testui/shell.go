@@ -0,0 +1,425 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/abiosoft/ishell/v2"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
+	"github.com/hashicorp/nomad-pack/internal/pkg/helper"
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+// InteractiveShellUI is a terminal.UI backed by a persistent ishell command
+// loop. It's used by `nomad-pack shell` to let users repeatedly render,
+// plan, and run packs against a preselected cluster/namespace without
+// paying CLI startup cost on every invocation.
+type InteractiveShellUI struct {
+	mu    sync.Mutex
+	shell *ishell.Shell
+	theme *terminal.Theme
+
+	// debug controls whether ErrorWithContext prints captured PackError
+	// stack traces. It's set from the CLI's --debug flag.
+	debug bool
+
+	OutWriter io.Writer
+	ErrWriter io.Writer
+
+	outMask *terminal.MaskingWriter
+}
+
+// Theme returns the active theme, falling back to terminal.DefaultTheme.
+func (ui *InteractiveShellUI) Theme() *terminal.Theme {
+	if ui.theme == nil {
+		return &terminal.DefaultTheme
+	}
+	return ui.theme
+}
+
+// SetDebug toggles whether ErrorWithContext prints PackError stack traces.
+func (ui *InteractiveShellUI) SetDebug(debug bool) {
+	ui.debug = debug
+}
+
+// Close flushes outMask. See nonInteractiveTestUI.Close.
+func (ui *InteractiveShellUI) Close() error {
+	if ui.outMask != nil {
+		return ui.outMask.Close()
+	}
+	return nil
+}
+
+// RegisterSecret implements UI. Because ishell prints whole lines through
+// its own Println/Printf rather than an io.Writer we control end-to-end,
+// Output/AppendToRow/NamedValues mask via outMask.MaskString before
+// printing, while OutputWriters() and TermOutput() still get the wrapped
+// io.Writer form for raw byte streams.
+func (ui *InteractiveShellUI) RegisterSecret(value string) {
+	ui.outMask.RegisterSecret(value)
+}
+
+// RegisterSecretPattern implements UI. See RegisterSecret.
+func (ui *InteractiveShellUI) RegisterSecretPattern(re *regexp.Regexp) {
+	ui.outMask.RegisterSecretPattern(re)
+}
+
+// ShellHandlers are the pack operations the `shell` builtins dispatch to.
+// They're supplied by the command package so this UI doesn't need to know
+// about registries, loaders, or Nomad clients.
+type ShellHandlers struct {
+	Use    func(registry string) error
+	Set    func(key, value string)
+	Vars   func() map[string]string
+	Plan   func() error
+	Run    func() error
+	Status func() error
+	Stop   func() error
+	Render func(diff bool) error
+
+	// Registries lists known registry names for `use`'s tab-completion.
+	// It may be nil or return nil until a real registry client is wired
+	// up; the completer degrades to no suggestions in that case.
+	Registries func() []string
+}
+
+// NewInteractiveShellUI constructs an InteractiveShellUI and registers the
+// builtin commands against the given handlers. Call Shell().Run() to start
+// the REPL.
+func NewInteractiveShellUI(ctx context.Context, handlers ShellHandlers) *InteractiveShellUI {
+	sh := ishell.New()
+	sh.SetPrompt("nomad-pack> ")
+
+	theme, err := terminal.LoadTheme()
+	if err != nil {
+		theme = terminal.DefaultTheme
+	}
+
+	outMask := terminal.NewMaskingWriter(sh)
+
+	ui := &InteractiveShellUI{
+		shell:     sh,
+		theme:     &theme,
+		OutWriter: outMask,
+		ErrWriter: outMask,
+		outMask:   outMask,
+	}
+
+	sh.AddCmd(&ishell.Cmd{
+		Name: "use",
+		Help: "select a registry to operate against",
+		Func: func(c *ishell.Context) {
+			if len(c.Args) != 1 {
+				ui.Error("usage: use <registry>")
+				return
+			}
+			if err := handlers.Use(c.Args[0]); err != nil {
+				ui.Error(err.Error())
+			}
+		},
+		Completer: func(args []string) []string {
+			if handlers.Registries == nil {
+				return nil
+			}
+			return handlers.Registries()
+		},
+	})
+
+	sh.AddCmd(&ishell.Cmd{
+		Name: "set",
+		Help: "set var=val for subsequent plan/run/render",
+		Func: func(c *ishell.Context) {
+			for _, arg := range c.Args {
+				key, value, found := strings.Cut(arg, "=")
+				if !found {
+					ui.Error("usage: set var=val")
+					continue
+				}
+				handlers.Set(key, value)
+			}
+		},
+		// Complete against already-set variable names, so re-editing a
+		// value is a tab-complete away; there's no pack loader yet to
+		// source the pack's declared-but-unset variables from.
+		Completer: func(args []string) []string {
+			vars := handlers.Vars()
+			names := make([]string, 0, len(vars))
+			for k := range vars {
+				names = append(names, k+"=")
+			}
+			slices.Sort(names)
+			return names
+		},
+	})
+
+	sh.AddCmd(&ishell.Cmd{
+		Name: "vars",
+		Help: "list currently set variables",
+		Func: func(c *ishell.Context) {
+			for k, v := range handlers.Vars() {
+				ui.Output(fmt.Sprintf("%s = %s", k, v))
+			}
+		},
+	})
+
+	sh.AddCmd(&ishell.Cmd{
+		Name: "plan",
+		Help: "plan the current pack",
+		Func: func(c *ishell.Context) { ui.runHandler(handlers.Plan) },
+	})
+
+	sh.AddCmd(&ishell.Cmd{
+		Name: "run",
+		Help: "run the current pack",
+		Func: func(c *ishell.Context) { ui.runHandler(handlers.Run) },
+	})
+
+	sh.AddCmd(&ishell.Cmd{
+		Name: "status",
+		Help: "show status of the current pack's deployment",
+		Func: func(c *ishell.Context) { ui.runHandler(handlers.Status) },
+	})
+
+	sh.AddCmd(&ishell.Cmd{
+		Name: "stop",
+		Help: "stop the current pack's deployment",
+		Func: func(c *ishell.Context) { ui.runHandler(handlers.Stop) },
+	})
+
+	sh.AddCmd(&ishell.Cmd{
+		Name: "render",
+		Help: "render the current pack's templates; pass --diff to diff against the last render",
+		Func: func(c *ishell.Context) {
+			diff := len(c.Args) == 1 && c.Args[0] == "--diff"
+			if err := handlers.Render(diff); err != nil {
+				ui.Error(err.Error())
+			}
+		},
+	})
+
+	return ui
+}
+
+func (ui *InteractiveShellUI) runHandler(fn func() error) {
+	if err := fn(); err != nil {
+		ui.Error(err.Error())
+	}
+}
+
+// Shell returns the underlying ishell.Shell so the command package can call
+// Run() or Close(). Builtins' tab-completers are registered in
+// NewInteractiveShellUI against ShellHandlers; command history is provided
+// automatically by ishell's underlying readline instance.
+func (ui *InteractiveShellUI) Shell() *ishell.Shell {
+	return ui.shell
+}
+
+func (ui *InteractiveShellUI) Input(input *terminal.Input) (string, error) {
+	ui.shell.Print(input.Prompt)
+	line := ui.shell.ReadLine()
+	return line, nil
+}
+
+func (ui *InteractiveShellUI) Interactive() bool {
+	return true
+}
+
+func (ui *InteractiveShellUI) Output(msg string, raw ...any) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	msg, style, _ := terminal.Interpret(msg, raw...)
+	theme := ui.Theme()
+	switch style {
+	case terminal.HeaderStyle:
+		ui.shell.Println(ui.outMask.MaskString(theme.Header.Color().Sprint(theme.Header.Prefix + msg)))
+	case terminal.ErrorStyle, terminal.ErrorBoldStyle:
+		ui.shell.Println(ui.outMask.MaskString(theme.Error.Color().Sprint(theme.Error.Prefix + msg)))
+	case terminal.WarningStyle, terminal.WarningBoldStyle:
+		ui.shell.Println(ui.outMask.MaskString(theme.Warning.Color().Sprint(theme.Warning.Prefix + msg)))
+	case terminal.SuccessStyle, terminal.SuccessBoldStyle:
+		ui.shell.Println(ui.outMask.MaskString(theme.Success.Color().Sprint(theme.Success.Prefix + msg)))
+	default:
+		ui.shell.Println(ui.outMask.MaskString(msg))
+	}
+}
+
+func (ui *InteractiveShellUI) AppendToRow(msg string, raw ...any) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	msg, _, _ = terminal.Interpret(msg, raw...)
+	ui.shell.Print(ui.outMask.MaskString(msg))
+}
+
+func (ui *InteractiveShellUI) NamedValues(rows []terminal.NamedValue, opts ...terminal.Option) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	for _, row := range rows {
+		ui.shell.Printf("  %s: %v\n", row.Name, ui.outMask.MaskString(fmt.Sprintf("%v", row.Value)))
+	}
+}
+
+func (ui *InteractiveShellUI) OutputWriters() (io.Writer, io.Writer, error) {
+	return ui.OutWriter, ui.ErrWriter, nil
+}
+
+func (ui *InteractiveShellUI) Status() terminal.Status {
+	return &nonInteractiveStatus{mu: &ui.mu, theme: ui.Theme(), outMask: ui.outMask}
+}
+
+func (ui *InteractiveShellUI) StepGroup() terminal.StepGroup {
+	return &shellStepGroup{ui: ui}
+}
+
+func (ui *InteractiveShellUI) Table(tbl *terminal.Table, opts ...terminal.Option) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	table := terminal.TableWithSettings(ui.shell, tbl.Headers)
+	table.Bulk(tbl.Rows)
+	table.Render()
+}
+
+func (ui *InteractiveShellUI) Debug(msg string) { ui.Output(msg, terminal.WithDebugStyle()) }
+func (ui *InteractiveShellUI) Error(msg string) { ui.Output(msg, terminal.WithErrorStyle()) }
+
+// ErrorWithContext mirrors nonInteractiveTestUI.ErrorWithContext so shell
+// users see the same PackError detail/suggestion/stack output and legacy
+// context promotion as every other UI backend.
+func (ui *InteractiveShellUI) ErrorWithContext(err error, sub string, ctx ...string) {
+	ui.Error(helper.Title(sub))
+	ui.Error("  Error: " + err.Error())
+
+	// Walk the cause chain, printing each layer's details and suggestions.
+	// A plain error (not wrapped in a PackError anywhere in its chain)
+	// falls straight through to the legacy string-prefix parsing below.
+	errors.Walk(err, func(packErr *errors.PackError) {
+		for _, detail := range packErr.Details {
+			ui.Error("  Detail: " + detail)
+		}
+		for _, suggestion := range packErr.Suggestions {
+			ui.Error("  Suggestion: " + suggestion)
+		}
+		if ui.debug && len(packErr.Stack) > 0 {
+			ui.Error("  Stack:")
+			ui.Error(packErr.FormatStack())
+		}
+	})
+
+	// Legacy path: selectively promote Details and Suggestion from the
+	// flat context slice for callers that haven't migrated to PackError.
+	var promote = func(key string) {
+		if oc, value, found := errors.ExtractContextItem(ctx, key); found {
+			ctx = oc
+			if value == "" {
+				ui.Error("  " + key)
+				return
+			}
+			ui.Error("  " + key + ": " + value)
+		}
+	}
+
+	promote(errors.UIContextErrorDetail)
+	promote(errors.UIContextErrorSuggestion)
+
+	ui.Error("  Context:")
+	max := 0
+	for _, entry := range ctx {
+		if loc := strings.Index(entry, ":") + 1; loc > max {
+			max = loc
+		}
+	}
+	for _, entry := range ctx {
+		padding := max - strings.Index(entry, ":") + 1
+		ui.Error("  " + strings.Repeat(" ", padding) + entry)
+	}
+}
+
+func (ui *InteractiveShellUI) Header(msg string)      { ui.Output(msg, terminal.WithHeaderStyle()) }
+func (ui *InteractiveShellUI) Info(msg string)        { ui.Output(msg, terminal.WithInfoStyle()) }
+func (ui *InteractiveShellUI) Success(msg string)     { ui.Output(msg, terminal.WithSuccessStyle()) }
+func (ui *InteractiveShellUI) Trace(msg string)       { ui.Output(msg, terminal.WithTraceStyle()) }
+func (ui *InteractiveShellUI) Warning(msg string)     { ui.Output(msg, terminal.WithWarningStyle()) }
+func (ui *InteractiveShellUI) WarningBold(msg string) {
+	ui.Output(msg, terminal.WithStyle(terminal.WarningBoldStyle))
+}
+
+// shellStepGroup renders each step inline below the prompt rather than
+// discarding status updates the way nonInteractiveStepGroup does.
+type shellStepGroup struct {
+	ui *InteractiveShellUI
+	wg sync.WaitGroup
+}
+
+func (g *shellStepGroup) Add(str string, args ...any) terminal.Step {
+	step := &shellStep{ui: g.ui, wg: &g.wg}
+	g.wg.Add(1)
+	step.Update(str, args...)
+	return step
+}
+
+func (g *shellStepGroup) Wait() {
+	g.wg.Wait()
+
+	if g.ui.outMask != nil {
+		g.ui.outMask.Close()
+	}
+}
+
+type shellStep struct {
+	ui   *InteractiveShellUI
+	wg   *sync.WaitGroup
+	done bool
+}
+
+func (s *shellStep) TermOutput() io.Writer {
+	return &stripAnsiWriter{Next: s.ui.outMask}
+}
+
+func (s *shellStep) Update(str string, args ...any) {
+	s.ui.mu.Lock()
+	defer s.ui.mu.Unlock()
+	s.ui.shell.Println(s.ui.outMask.MaskString("-> " + fmt.Sprintf(str, args...)))
+}
+
+// Progress implements terminal.Step. The shell UI has no live repainting,
+// so ticks are printed as plain lines.
+func (s *shellStep) Progress(current, total int64, unit string) {
+	s.ui.mu.Lock()
+	defer s.ui.mu.Unlock()
+	s.ui.shell.Println(s.ui.outMask.MaskString(fmt.Sprintf("   %d/%d %s", current, total, unit)))
+}
+
+// SubStep implements terminal.Step.
+func (s *shellStep) SubStep(name string) {
+	s.ui.mu.Lock()
+	defer s.ui.mu.Unlock()
+	s.ui.shell.Println(s.ui.outMask.MaskString("   - " + name))
+}
+
+func (s *shellStep) Status(status string) {
+	s.ui.mu.Lock()
+	defer s.ui.mu.Unlock()
+	s.ui.shell.Println(statusGlyph(s.ui.Theme(), status))
+}
+
+func (s *shellStep) Done() {
+	s.ui.mu.Lock()
+	defer s.ui.mu.Unlock()
+	if s.done {
+		return
+	}
+	s.done = true
+	s.wg.Done()
+}
+
+func (s *shellStep) Abort() {
+	s.Done()
+}
@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testui
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestInteractiveShellUI_DispatchAndMasking drives the `set`/`vars`
+// builtins through ishell's own command dispatch (rather than calling
+// ShellHandlers directly) and asserts a registered secret set via `set`
+// doesn't leak back out through `vars`.
+func TestInteractiveShellUI_DispatchAndMasking(t *testing.T) {
+	var mu sync.Mutex
+	values := make(map[string]string)
+
+	ui := NewInteractiveShellUI(context.Background(), ShellHandlers{
+		Use: func(registry string) error { return nil },
+		Set: func(key, value string) {
+			mu.Lock()
+			defer mu.Unlock()
+			values[key] = value
+		},
+		Vars: func() map[string]string {
+			mu.Lock()
+			defer mu.Unlock()
+			out := make(map[string]string, len(values))
+			for k, v := range values {
+				out[k] = v
+			}
+			return out
+		},
+	})
+
+	var out bytes.Buffer
+	ui.shell.SetOut(&out)
+	ui.RegisterSecret("s3cr3t-token")
+
+	if _, err := ui.shell.Process("set", "token=s3cr3t-token"); err != nil {
+		t.Fatalf("Process(set): %v", err)
+	}
+
+	mu.Lock()
+	got := values["token"]
+	mu.Unlock()
+	if got != "s3cr3t-token" {
+		t.Fatalf("expected set to dispatch to handlers.Set, got values[token] = %q", got)
+	}
+
+	if _, err := ui.shell.Process("vars"); err != nil {
+		t.Fatalf("Process(vars): %v", err)
+	}
+
+	if strings.Contains(out.String(), "s3cr3t-token") {
+		t.Fatalf("secret leaked through shell vars dispatch: %q", out.String())
+	}
+}
@@ -0,0 +1,415 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
+	"github.com/hashicorp/nomad-pack/internal/pkg/helper"
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+// structuredRecord is the shape of every line a StructuredUI writes. Fields
+// are intentionally flat so downstream consumers can jq/grep them without
+// knowing which UI call produced them.
+type structuredRecord struct {
+	Timestamp   string                `json:"ts"`
+	Level       string                `json:"level"`
+	Msg         string                `json:"msg,omitempty"`
+	Error       string                `json:"error,omitempty"`
+	Details     []string              `json:"details,omitempty"`
+	Suggestions []string              `json:"suggestions,omitempty"`
+	Stack       []string              `json:"stack,omitempty"`
+	Context     map[string]string     `json:"context,omitempty"`
+	Values      []terminal.NamedValue `json:"values,omitempty"`
+	Table       *structuredTable      `json:"table,omitempty"`
+	Step        string                `json:"step,omitempty"`
+	Status      string                `json:"status,omitempty"`
+}
+
+type structuredTable struct {
+	Headers []string   `json:"headers"`
+	Rows    [][]string `json:"rows"`
+}
+
+// StructuredUI is a terminal.UI implementation that emits one JSON object
+// per line on the out/err writers, instead of formatting for a human
+// terminal. It's intended for CI and other machine consumers that would
+// otherwise have to regex-scrape ANSI-stripped text.
+type StructuredUI struct {
+	mu        sync.Mutex
+	OutWriter io.Writer
+	ErrWriter io.Writer
+
+	// debug controls whether ErrorWithContext includes PackError stack
+	// traces in emitted records. It's set from the CLI's --debug flag.
+	debug bool
+
+	outMask *terminal.MaskingWriter
+	errMask *terminal.MaskingWriter
+}
+
+// SetDebug toggles whether ErrorWithContext includes PackError stack
+// traces.
+func (ui *StructuredUI) SetDebug(debug bool) {
+	ui.debug = debug
+}
+
+// Close flushes outMask/errMask. See nonInteractiveTestUI.Close.
+func (ui *StructuredUI) Close() error {
+	ui.mu.Lock()
+	outMask, errMask := ui.outMask, ui.errMask
+	ui.mu.Unlock()
+
+	var err error
+	if outMask != nil {
+		err = outMask.Close()
+	}
+	if errMask != nil {
+		if errMaskErr := errMask.Close(); err == nil {
+			err = errMaskErr
+		}
+	}
+	return err
+}
+
+// StructuredJSONUI returns a StructuredUI that writes each record as a
+// standalone JSON line (NDJSON) to stdout, and errors to stderr.
+func StructuredJSONUI(ctx context.Context, stdout io.Writer, stderr io.Writer) terminal.UI {
+	ui := &StructuredUI{
+		OutWriter: stdout,
+		ErrWriter: stderr,
+	}
+	ui.ensureMasks()
+	return ui
+}
+
+// ensureMasks wraps OutWriter/ErrWriter in a MaskingWriter the first time
+// it's called. See nonInteractiveTestUI.ensureMasks.
+func (ui *StructuredUI) ensureMasks() {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	if ui.outMask == nil {
+		ui.outMask = terminal.NewMaskingWriter(ui.OutWriter)
+		ui.OutWriter = ui.outMask
+	}
+	if ui.errMask == nil {
+		ui.errMask = terminal.NewMaskingWriter(ui.ErrWriter)
+		ui.ErrWriter = ui.errMask
+	}
+}
+
+// RegisterSecret implements UI. See nonInteractiveTestUI.RegisterSecret.
+func (ui *StructuredUI) RegisterSecret(value string) {
+	ui.ensureMasks()
+	ui.outMask.RegisterSecret(value)
+	ui.errMask.RegisterSecret(value)
+}
+
+// RegisterSecretPattern implements UI. See RegisterSecret.
+func (ui *StructuredUI) RegisterSecretPattern(re *regexp.Regexp) {
+	ui.ensureMasks()
+	ui.outMask.RegisterSecretPattern(re)
+	ui.errMask.RegisterSecretPattern(re)
+}
+
+func (ui *StructuredUI) now() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+func (ui *StructuredUI) emit(w io.Writer, rec structuredRecord) {
+	if rec.Timestamp == "" {
+		rec.Timestamp = ui.now()
+	}
+	enc := json.NewEncoder(w)
+	// Errors writing to out/err are not actionable by the UI layer; the
+	// caller will notice a broken pipe long before this would matter.
+	_ = enc.Encode(rec)
+}
+
+func (ui *StructuredUI) Input(input *terminal.Input) (string, error) {
+	return "", terminal.ErrNonInteractive
+}
+
+func (ui *StructuredUI) Interactive() bool {
+	return false
+}
+
+func (ui *StructuredUI) Output(msg string, raw ...any) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	msg, style, _ := terminal.Interpret(msg, raw...)
+	ui.emit(ui.OutWriter, structuredRecord{Level: levelForStyle(style), Msg: msg})
+}
+
+func (ui *StructuredUI) AppendToRow(msg string, raw ...any) {
+	// There's no concept of "the current row" in a line-oriented format;
+	// treat it like any other output record.
+	ui.Output(msg, raw...)
+}
+
+func (ui *StructuredUI) NamedValues(rows []terminal.NamedValue, opts ...terminal.Option) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	ui.emit(ui.OutWriter, structuredRecord{Level: "info", Values: rows})
+}
+
+func (ui *StructuredUI) OutputWriters() (io.Writer, io.Writer, error) {
+	return ui.OutWriter, ui.ErrWriter, nil
+}
+
+func (ui *StructuredUI) Status() terminal.Status {
+	return &structuredStatus{ui: ui}
+}
+
+func (ui *StructuredUI) StepGroup() terminal.StepGroup {
+	return &structuredStepGroup{ui: ui}
+}
+
+func (ui *StructuredUI) Table(tbl *terminal.Table, opts ...terminal.Option) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	ui.emit(ui.OutWriter, structuredRecord{
+		Level: "info",
+		Table: &structuredTable{Headers: tbl.Headers, Rows: tbl.Rows},
+	})
+}
+
+func (ui *StructuredUI) Debug(msg string) {
+	ui.Output(msg, terminal.WithDebugStyle())
+}
+
+func (ui *StructuredUI) Error(msg string) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	ui.emit(ui.ErrWriter, structuredRecord{Level: "error", Msg: msg})
+}
+
+// ErrorWithContext satisfies the ErrorWithContext function on the UI
+// interface, emitting the error, its promoted detail/suggestion, and the
+// remaining context as a single structured record rather than padded text.
+func (ui *StructuredUI) ErrorWithContext(err error, sub string, ctx ...string) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	rec := structuredRecord{
+		Level: "error",
+		Msg:   helper.Title(sub),
+		Error: err.Error(),
+	}
+
+	errors.Walk(err, func(packErr *errors.PackError) {
+		rec.Details = append(rec.Details, packErr.Details...)
+		rec.Suggestions = append(rec.Suggestions, packErr.Suggestions...)
+		if ui.debug && len(packErr.Stack) > 0 {
+			rec.Stack = append(rec.Stack, packErr.FormatStack())
+		}
+	})
+
+	if oc, detail, found := errors.ExtractContextItem(ctx, errors.UIContextErrorDetail); found {
+		ctx = oc
+		rec.Details = append(rec.Details, detail)
+	}
+	if oc, suggestion, found := errors.ExtractContextItem(ctx, errors.UIContextErrorSuggestion); found {
+		ctx = oc
+		rec.Suggestions = append(rec.Suggestions, suggestion)
+	}
+
+	if len(ctx) > 0 {
+		rec.Context = make(map[string]string, len(ctx))
+		for _, entry := range ctx {
+			k, v, found := strings.Cut(entry, ":")
+			if !found {
+				rec.Context[entry] = ""
+				continue
+			}
+			rec.Context[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+
+	ui.emit(ui.ErrWriter, rec)
+}
+
+func (ui *StructuredUI) Header(msg string) {
+	ui.Output(msg, terminal.WithHeaderStyle())
+}
+
+func (ui *StructuredUI) Info(msg string) {
+	ui.Output(msg, terminal.WithInfoStyle())
+}
+
+func (ui *StructuredUI) Success(msg string) {
+	ui.Output(msg, terminal.WithSuccessStyle())
+}
+
+func (ui *StructuredUI) Trace(msg string) {
+	ui.Output(msg, terminal.WithTraceStyle())
+}
+
+func (ui *StructuredUI) Warning(msg string) {
+	ui.Output(msg, terminal.WithWarningStyle())
+}
+
+func (ui *StructuredUI) WarningBold(msg string) {
+	ui.Output(msg, terminal.WithStyle(terminal.WarningBoldStyle))
+}
+
+func levelForStyle(style terminal.Style) string {
+	switch style {
+	case terminal.DebugStyle:
+		return "debug"
+	case terminal.ErrorStyle, terminal.ErrorBoldStyle:
+		return "error"
+	case terminal.WarningStyle, terminal.WarningBoldStyle:
+		return "warning"
+	case terminal.TraceStyle:
+		return "trace"
+	case terminal.SuccessStyle, terminal.SuccessBoldStyle:
+		return "success"
+	case terminal.HeaderStyle:
+		return "header"
+	default:
+		return "info"
+	}
+}
+
+type structuredStatus struct {
+	ui *StructuredUI
+}
+
+func (s *structuredStatus) Update(msg string) {
+	s.ui.mu.Lock()
+	defer s.ui.mu.Unlock()
+	s.ui.emit(s.ui.OutWriter, structuredRecord{Level: "status", Msg: msg})
+}
+
+func (s *structuredStatus) Step(status, msg string) {
+	s.ui.mu.Lock()
+	defer s.ui.mu.Unlock()
+	s.ui.emit(s.ui.OutWriter, structuredRecord{Level: "status", Status: status, Msg: msg})
+}
+
+// Close flushes the UI's outMask. See nonInteractiveTestUI.Close.
+func (s *structuredStatus) Close() error {
+	if s.ui.outMask != nil {
+		return s.ui.outMask.Close()
+	}
+	return nil
+}
+
+type structuredStepGroup struct {
+	ui *StructuredUI
+	wg sync.WaitGroup
+}
+
+func (g *structuredStepGroup) Add(str string, args ...any) terminal.Step {
+	step := &structuredStep{ui: g.ui, wg: &g.wg}
+	g.wg.Add(1)
+	step.Update(str, args...)
+	return step
+}
+
+func (g *structuredStepGroup) Wait() {
+	g.wg.Wait()
+
+	// Flush any tail bytes outMask withheld to catch a secret split across
+	// writes - no further steps will be added to this group once Wait
+	// returns.
+	if g.ui.outMask != nil {
+		g.ui.outMask.Close()
+	}
+}
+
+type structuredStep struct {
+	ui   *StructuredUI
+	wg   *sync.WaitGroup
+	name string
+	done bool
+}
+
+func (s *structuredStep) TermOutput() io.Writer {
+	return &structuredStepWriter{ui: s.ui, step: s.name}
+}
+
+func (s *structuredStep) Update(str string, args ...any) {
+	name := sprintfIf(str, args)
+	s.name = name
+	s.ui.mu.Lock()
+	defer s.ui.mu.Unlock()
+	s.ui.emit(s.ui.OutWriter, structuredRecord{Level: "step", Step: name, Msg: name})
+}
+
+// Progress implements terminal.Step, emitting a record per tick so a CI
+// consumer can reconstruct a progress bar without terminal repainting.
+func (s *structuredStep) Progress(current, total int64, unit string) {
+	s.ui.mu.Lock()
+	defer s.ui.mu.Unlock()
+	s.ui.emit(s.ui.OutWriter, structuredRecord{
+		Level: "progress",
+		Step:  s.name,
+		Msg:   fmt.Sprintf("%d/%d %s", current, total, unit),
+	})
+}
+
+// SubStep implements terminal.Step.
+func (s *structuredStep) SubStep(name string) {
+	s.ui.mu.Lock()
+	defer s.ui.mu.Unlock()
+	s.ui.emit(s.ui.OutWriter, structuredRecord{Level: "step", Step: s.name, Msg: name})
+}
+
+func (s *structuredStep) Status(status string) {
+	s.ui.mu.Lock()
+	defer s.ui.mu.Unlock()
+	s.ui.emit(s.ui.OutWriter, structuredRecord{Level: "step", Step: s.name, Status: status})
+}
+
+func (s *structuredStep) Done() {
+	s.ui.mu.Lock()
+	if s.done {
+		s.ui.mu.Unlock()
+		return
+	}
+	s.done = true
+	s.ui.mu.Unlock()
+	s.wg.Done()
+}
+
+func (s *structuredStep) Abort() {
+	s.Done()
+}
+
+// structuredStepWriter adapts a step's raw TermOutput into discrete
+// structured records, one per Write call; a Write holding several lines,
+// or only part of one, becomes a single record.
+type structuredStepWriter struct {
+	ui   *StructuredUI
+	step string
+}
+
+func (w *structuredStepWriter) Write(p []byte) (int, error) {
+	w.ui.mu.Lock()
+	defer w.ui.mu.Unlock()
+	w.ui.emit(w.ui.OutWriter, structuredRecord{Level: "step", Step: w.step, Msg: string(p)})
+	return len(p), nil
+}
+
+func sprintfIf(str string, args []any) string {
+	if len(args) == 0 {
+		return str
+	}
+	return fmt.Sprintf(str, args...)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// TestStructuredUI_StepSequenceExpandsName guards against the step "name"
+// staying an unexpanded fmt.Sprintf template: Add/Update must resolve
+// "deploying %s" against its args once, and every later SubStep/Progress/
+// Status record for that step must reuse the resolved name, not the
+// template, in its "step" field.
+func TestStructuredUI_StepSequenceExpandsName(t *testing.T) {
+	var out bytes.Buffer
+	ui := StructuredJSONUI(context.Background(), &out, &bytes.Buffer{})
+
+	sg := ui.StepGroup()
+	step := sg.Add("deploying %s", "mypack")
+	step.SubStep("rendering")
+	step.Progress(1, 2, "allocations")
+	step.Status("ok")
+	step.Done()
+	sg.Wait()
+
+	dec := json.NewDecoder(&out)
+	var records []structuredRecord
+	for {
+		var rec structuredRecord
+		if err := dec.Decode(&rec); err != nil {
+			break
+		}
+		records = append(records, rec)
+	}
+
+	if len(records) == 0 {
+		t.Fatal("expected at least one record")
+	}
+	for _, rec := range records {
+		if rec.Step == "" {
+			continue
+		}
+		if rec.Step != "deploying mypack" {
+			t.Fatalf("expected step name %q, got %q in record %+v", "deploying mypack", rec.Step, rec)
+		}
+	}
+}
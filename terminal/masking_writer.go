@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// maskedReplacement is written in place of any matched secret.
+const maskedReplacement = "***"
+
+// MaskingWriter wraps an io.Writer and redacts any value registered via
+// RegisterSecret or RegisterSecretPattern before it reaches the
+// underlying writer. It's applied to OutWriter, ErrWriter, and each
+// step's TermOutput so that sensitive pack variables and Nomad ACL tokens
+// never leak to the terminal, regardless of which UI call produced them.
+//
+// Secrets can be split across separate Write calls (a long token
+// straddling a buffer boundary), so MaskingWriter holds back a small tail
+// of unflushed bytes — the length of the longest registered secret, minus
+// one — until the next Write call provides enough context to know
+// whether it completes a match.
+type MaskingWriter struct {
+	mu   sync.Mutex
+	next io.Writer
+
+	secrets  []string
+	patterns []*regexp.Regexp
+	maxLen   int
+
+	tail []byte
+}
+
+// NewMaskingWriter returns a MaskingWriter wrapping next.
+func NewMaskingWriter(next io.Writer) *MaskingWriter {
+	return &MaskingWriter{next: next}
+}
+
+// RegisterSecret adds a literal value to redact. Empty values are ignored
+// since masking them would redact everything.
+func (w *MaskingWriter) RegisterSecret(value string) {
+	if value == "" {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.secrets = append(w.secrets, value)
+	// Matching longest-first keeps one secret from shadowing a longer
+	// secret that contains it as a substring.
+	sort.Slice(w.secrets, func(i, j int) bool { return len(w.secrets[i]) > len(w.secrets[j]) })
+
+	if len(value) > w.maxLen {
+		w.maxLen = len(value)
+	}
+}
+
+// RegisterSecretPattern adds a regexp whose matches are redacted. Patterns
+// are not covered by the tail buffer sizing, so a pattern match split
+// exactly across a Write boundary can slip through; register literal
+// secrets where that matters.
+func (w *MaskingWriter) RegisterSecretPattern(re *regexp.Regexp) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.patterns = append(w.patterns, re)
+}
+
+// Write implements io.Writer.
+func (w *MaskingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.tail = append(w.tail, p...)
+
+	keep := w.maxLen - 1
+	if keep < 0 {
+		keep = 0
+	}
+	if len(w.tail) <= keep {
+		return len(p), nil
+	}
+
+	// Mask the whole buffer before splitting it, so a secret straddling the
+	// flush boundary is caught. A partial match at the end is at most keep
+	// bytes long and stays in the tail for the next Write.
+	masked := w.mask(w.tail)
+	if len(masked) <= keep {
+		w.tail = masked
+		return len(p), nil
+	}
+
+	flush := masked[:len(masked)-keep]
+	w.tail = append([]byte(nil), masked[len(masked)-keep:]...)
+
+	if _, err := w.next.Write(flush); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close flushes any buffered tail bytes through the masker. Callers that
+// hold a MaskingWriter past the life of a single Write burst (a step's
+// TermOutput, a UI's OutWriter) should Close it on shutdown so the last
+// few bytes aren't dropped silently.
+func (w *MaskingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.tail) == 0 {
+		return nil
+	}
+	defer func() { w.tail = nil }()
+
+	_, err := w.next.Write(w.mask(w.tail))
+	return err
+}
+
+// MaskString redacts registered secrets from a complete, already-assembled
+// string, bypassing the tail buffer used by Write. It's for UIs that print
+// whole lines directly (e.g. through a REPL library's own Println) rather
+// than writing through io.Writer.
+func (w *MaskingWriter) MaskString(s string) string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return string(w.mask([]byte(s)))
+}
+
+func (w *MaskingWriter) mask(p []byte) []byte {
+	out := p
+	for _, secret := range w.secrets {
+		out = bytes.ReplaceAll(out, []byte(secret), []byte(maskedReplacement))
+	}
+	for _, pattern := range w.patterns {
+		out = pattern.ReplaceAll(out, []byte(maskedReplacement))
+	}
+	return out
+}
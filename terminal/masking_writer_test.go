@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestMaskingWriter_Write(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewMaskingWriter(&buf)
+	w.RegisterSecret("s3cr3t-token")
+
+	if _, err := w.Write([]byte("login with s3cr3t-token please\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if got := buf.String(); bytes.Contains([]byte(got), []byte("s3cr3t-token")) {
+		t.Fatalf("secret leaked through Write: %q", got)
+	}
+}
+
+func TestMaskingWriter_WriteSplitAcrossCalls(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewMaskingWriter(&buf)
+	secret := "s3cr3t-token"
+	w.RegisterSecret(secret)
+
+	// Split the secret across two Write calls, the way a streamed command
+	// output or a chunked HTTP body would.
+	first := "login with s3cr3t-tok"
+	second := "en please\n"
+	if _, err := w.Write([]byte(first)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, err := w.Write([]byte(second)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if got := buf.String(); bytes.Contains([]byte(got), []byte(secret)) {
+		t.Fatalf("secret split across Write calls leaked: %q", got)
+	}
+}
+
+func TestMaskingWriter_CloseFlushesTail(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewMaskingWriter(&buf)
+	w.RegisterSecret("s3cr3t-token")
+
+	// A write shorter than maxLen-1 is held entirely in the tail buffer
+	// until Close flushes it; without Close these bytes never reach buf.
+	if _, err := w.Write([]byte("short")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing flushed before Close, got %q", buf.String())
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := buf.String(); got != "short" {
+		t.Fatalf("Close did not flush withheld tail: got %q", got)
+	}
+}
+
+func TestMaskingWriter_MaskString(t *testing.T) {
+	w := NewMaskingWriter(nil)
+	w.RegisterSecret("s3cr3t-token")
+	w.RegisterSecretPattern(regexp.MustCompile(`nomad-[a-f0-9]{8}`))
+
+	// MaskString is used by UIs that print whole, already-formatted lines
+	// (NamedValues through a tabwriter, ishell's Println) rather than
+	// writing through io.Writer, so tabs/newlines in the input must be
+	// preserved around the redaction.
+	in := "  token: \ts3cr3t-token\n  alloc:  \tnomad-deadbeef\n"
+	out := w.MaskString(in)
+
+	if bytes.Contains([]byte(out), []byte("s3cr3t-token")) {
+		t.Fatalf("literal secret leaked through MaskString: %q", out)
+	}
+	if bytes.Contains([]byte(out), []byte("nomad-deadbeef")) {
+		t.Fatalf("pattern secret leaked through MaskString: %q", out)
+	}
+	if !bytes.Contains([]byte(out), []byte("token: \t***\n")) {
+		t.Fatalf("expected formatting around the redaction to survive, got %q", out)
+	}
+}
+
+func TestMaskingWriter_EmptySecretIgnored(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewMaskingWriter(&buf)
+	w.RegisterSecret("")
+
+	if _, err := w.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := buf.String(); got != "hello\n" {
+		t.Fatalf("registering an empty secret should not redact everything, got %q", got)
+	}
+}
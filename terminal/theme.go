@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/hashicorp/hcl/v2/hclsimple"
+	"gopkg.in/yaml.v3"
+)
+
+// StyleDef is one entry in a Theme: the prefix and color applied to a
+// single UI style (Header, Info, Success, ...).
+type StyleDef struct {
+	Prefix    string `yaml:"prefix" hcl:"prefix,optional"`
+	ColorName string `yaml:"color" hcl:"color,optional"`
+	Bold      bool   `yaml:"bold" hcl:"bold,optional"`
+}
+
+// Theme maps each UI style, plus the step status glyphs, to a prefix
+// string and a color. nonInteractiveTestUI and the interactive UIs look up
+// their prefixes/colors here instead of hardcoding "\n» ", "! ",
+// "warning: ", and friends.
+type Theme struct {
+	Name string `yaml:"name" hcl:"name,optional"`
+
+	Header  StyleDef `yaml:"header" hcl:"header,block"`
+	Info    StyleDef `yaml:"info" hcl:"info,block"`
+	Success StyleDef `yaml:"success" hcl:"success,block"`
+	Warning StyleDef `yaml:"warning" hcl:"warning,block"`
+	Error   StyleDef `yaml:"error" hcl:"error,block"`
+	Debug   StyleDef `yaml:"debug" hcl:"debug,block"`
+	Trace   StyleDef `yaml:"trace" hcl:"trace,block"`
+
+	StatusOK      string `yaml:"status_ok" hcl:"status_ok,optional"`
+	StatusError   string `yaml:"status_error" hcl:"status_error,optional"`
+	StatusWarn    string `yaml:"status_warn" hcl:"status_warn,optional"`
+	StatusTimeout string `yaml:"status_timeout" hcl:"status_timeout,optional"`
+}
+
+// forceColorOnce resolves FORCE_COLOR's override of fatih/color's global
+// color.NoColor exactly once. Color() takes no lock and can be called from
+// multiple goroutines (each UI serializes only its own output), so
+// mutating color.NoColor on every call -
+// the previous behavior - was an unsynchronized write to a package global
+// and a data race under -race.
+var forceColorOnce sync.Once
+
+// Color returns a *color.Color for the given StyleDef, honoring NO_COLOR
+// and FORCE_COLOR as described at https://no-color.org and its informal
+// FORCE_COLOR counterpart. FORCE_COLOR takes precedence over NO_COLOR, and
+// also overrides fatih/color's own TTY autodetection - without that,
+// piping output to a file or CI log would still disable color even with
+// FORCE_COLOR set, defeating its main use case.
+func (s StyleDef) Color() *color.Color {
+	if os.Getenv("FORCE_COLOR") != "" {
+		forceColorOnce.Do(func() { color.NoColor = false })
+	} else if _, noColor := os.LookupEnv("NO_COLOR"); noColor {
+		return color.New()
+	}
+
+	attrs := []color.Attribute{colorByName(s.ColorName)}
+	if s.Bold {
+		attrs = append(attrs, color.Bold)
+	}
+	return color.New(attrs...)
+}
+
+func colorByName(name string) color.Attribute {
+	switch name {
+	case "red":
+		return color.FgRed
+	case "green":
+		return color.FgGreen
+	case "yellow":
+		return color.FgYellow
+	case "blue":
+		return color.FgBlue
+	case "magenta":
+		return color.FgMagenta
+	case "cyan":
+		return color.FgCyan
+	case "white":
+		return color.FgWhite
+	default:
+		return color.Reset
+	}
+}
+
+// DefaultTheme is used whenever no theme file or NOMAD_PACK_THEME is
+// configured.
+var DefaultTheme = Theme{
+	Name:          "default",
+	Header:        StyleDef{Prefix: "\n» ", Bold: true},
+	Info:          StyleDef{Prefix: "  "},
+	Success:       StyleDef{Prefix: "", ColorName: "green"},
+	Warning:       StyleDef{Prefix: "warning: ", ColorName: "yellow"},
+	Error:         StyleDef{Prefix: "! ", ColorName: "red"},
+	Debug:         StyleDef{Prefix: "debug: "},
+	Trace:         StyleDef{Prefix: "trace: "},
+	StatusOK:      " +",
+	StatusError:   " !",
+	StatusWarn:    " *",
+	StatusTimeout: "<>",
+}
+
+// builtinThemes are selectable by name via NOMAD_PACK_THEME=<name> without
+// needing a config file on disk.
+var builtinThemes = map[string]Theme{
+	"default": DefaultTheme,
+	"dark":    DefaultTheme,
+	"light": {
+		Name:          "light",
+		Header:        StyleDef{Prefix: "\n» ", Bold: true, ColorName: "blue"},
+		Info:          StyleDef{Prefix: "  "},
+		Success:       StyleDef{Prefix: "", ColorName: "green"},
+		Warning:       StyleDef{Prefix: "warning: ", ColorName: "yellow"},
+		Error:         StyleDef{Prefix: "! ", ColorName: "red"},
+		Debug:         StyleDef{Prefix: "debug: "},
+		Trace:         StyleDef{Prefix: "trace: "},
+		StatusOK:      " +",
+		StatusError:   " !",
+		StatusWarn:    " *",
+		StatusTimeout: "<>",
+	},
+	"high-contrast": {
+		Name:          "high-contrast",
+		Header:        StyleDef{Prefix: "\n» ", Bold: true, ColorName: "white"},
+		Info:          StyleDef{Prefix: "  ", Bold: true},
+		Success:       StyleDef{Prefix: "[OK] ", ColorName: "green", Bold: true},
+		Warning:       StyleDef{Prefix: "[WARN] ", ColorName: "yellow", Bold: true},
+		Error:         StyleDef{Prefix: "[ERROR] ", ColorName: "red", Bold: true},
+		Debug:         StyleDef{Prefix: "[DEBUG] "},
+		Trace:         StyleDef{Prefix: "[TRACE] "},
+		StatusOK:      "[OK]",
+		StatusError:   "[ERR]",
+		StatusWarn:    "[WARN]",
+		StatusTimeout: "[TIMEOUT]",
+	},
+	"ascii-only": {
+		Name:          "ascii-only",
+		Header:        StyleDef{Prefix: "\n> "},
+		Info:          StyleDef{Prefix: "  "},
+		Success:       StyleDef{Prefix: ""},
+		Warning:       StyleDef{Prefix: "warning: "},
+		Error:         StyleDef{Prefix: "! "},
+		Debug:         StyleDef{Prefix: "debug: "},
+		Trace:         StyleDef{Prefix: "trace: "},
+		StatusOK:      " +",
+		StatusError:   " !",
+		StatusWarn:    " *",
+		StatusTimeout: "<>",
+	},
+}
+
+// LoadTheme resolves the active theme: NOMAD_PACK_THEME wins if set to a
+// builtin name or a path to a YAML/HCL theme file; otherwise it looks for
+// ~/.config/nomad-pack/theme.{yaml,hcl}; otherwise DefaultTheme.
+func LoadTheme() (Theme, error) {
+	if name := os.Getenv("NOMAD_PACK_THEME"); name != "" {
+		if t, ok := builtinThemes[name]; ok {
+			return t, nil
+		}
+		return loadThemeFile(name)
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err == nil {
+		for _, ext := range []string{"yaml", "hcl"} {
+			path := filepath.Join(configDir, "nomad-pack", "theme."+ext)
+			if _, statErr := os.Stat(path); statErr == nil {
+				return loadThemeFile(path)
+			}
+		}
+	}
+
+	return DefaultTheme, nil
+}
+
+func loadThemeFile(path string) (Theme, error) {
+	theme := DefaultTheme
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, fmt.Errorf("reading theme file %q: %w", path, err)
+	}
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(raw, &theme); err != nil {
+			return Theme{}, fmt.Errorf("parsing theme file %q: %w", path, err)
+		}
+	case ".hcl":
+		if err := hclsimple.Decode(path, raw, nil, &theme); err != nil {
+			return Theme{}, fmt.Errorf("parsing theme file %q: %w", path, err)
+		}
+	default:
+		return Theme{}, fmt.Errorf("unrecognized theme file extension for %q", path)
+	}
+
+	return theme, nil
+}
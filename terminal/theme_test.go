@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestLoadTheme_Unset(t *testing.T) {
+	t.Setenv("NOMAD_PACK_THEME", "")
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	theme, err := LoadTheme()
+	if err != nil {
+		t.Fatalf("LoadTheme: %v", err)
+	}
+	if theme.Name != DefaultTheme.Name {
+		t.Fatalf("expected DefaultTheme, got theme %q", theme.Name)
+	}
+}
+
+func TestLoadTheme_BuiltinName(t *testing.T) {
+	t.Setenv("NOMAD_PACK_THEME", "high-contrast")
+
+	theme, err := LoadTheme()
+	if err != nil {
+		t.Fatalf("LoadTheme: %v", err)
+	}
+	if theme.Name != "high-contrast" {
+		t.Fatalf("expected high-contrast theme, got %q", theme.Name)
+	}
+}
+
+func TestLoadTheme_FilePath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.yaml")
+	const doc = `
+name: custom
+header:
+  prefix: ">> "
+  color: blue
+  bold: true
+`
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	t.Setenv("NOMAD_PACK_THEME", path)
+
+	theme, err := LoadTheme()
+	if err != nil {
+		t.Fatalf("LoadTheme: %v", err)
+	}
+	if theme.Name != "custom" {
+		t.Fatalf("expected custom theme, got %q", theme.Name)
+	}
+	if theme.Header.Prefix != ">> " || theme.Header.ColorName != "blue" || !theme.Header.Bold {
+		t.Fatalf("expected parsed header style, got %+v", theme.Header)
+	}
+}
+
+// TestStyleDef_Color_ConcurrentForceColor guards against the
+// color.NoColor data race this fix addresses: with FORCE_COLOR set,
+// concurrent Color() calls from multiple goroutines sharing a style must
+// resolve the override via
+// forceColorOnce rather than racing on the unsynchronized package global.
+// Run with -race to verify.
+func TestStyleDef_Color_ConcurrentForceColor(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "1")
+
+	style := StyleDef{ColorName: "green"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = style.Color()
+		}()
+	}
+	wg.Wait()
+}